@@ -0,0 +1,81 @@
+// Package filter decides which mounted partitions diskinfo should report,
+// based on filesystem type and mountpoint include/exclude lists.
+package filter
+
+// DefaultExcludedFstypes are pseudo filesystems that never represent real
+// disk capacity: virtual memory-backed filesystems, kernel interfaces, and
+// the squashfs/overlay layers snap and docker mount loopback images
+// through. They're skipped unless the caller explicitly asks for them via
+// IncludeFstypes, or passes its own ExcludeFstypes, which replaces this
+// list rather than adding to it.
+var DefaultExcludedFstypes = []string{
+	"tmpfs", "devtmpfs", "overlay", "squashfs",
+	"proc", "sysfs", "cgroup", "cgroup2", "autofs",
+	"devpts", "mqueue", "debugfs", "tracefs", "pstore", "securityfs", "bpf",
+}
+
+// Options configures a Filter. Empty slices mean "no restriction" for
+// include lists and "use the default" for ExcludeFstypes.
+type Options struct {
+	IncludeFstypes []string
+	ExcludeFstypes []string
+	IncludeMounts  []string
+	ExcludeMounts  []string
+}
+
+// Filter tests mounted partitions against include/exclude lists.
+type Filter struct {
+	includeFstype set
+	excludeFstype set
+	includeMount  set
+	excludeMount  set
+}
+
+type set map[string]bool
+
+func toSet(values []string) set {
+	s := make(set, len(values))
+	for _, v := range values {
+		s[v] = true
+	}
+	return s
+}
+
+// New builds a Filter from opts. When opts.ExcludeFstypes is empty, the
+// default pseudo-filesystem skiplist is used instead (callers that pass
+// IncludeFstypes to override everything still get the fstypes they asked
+// for, since include lists take priority over excludes).
+func New(opts Options) *Filter {
+	excludeFstypes := opts.ExcludeFstypes
+	if len(excludeFstypes) == 0 {
+		excludeFstypes = DefaultExcludedFstypes
+	}
+	return &Filter{
+		includeFstype: toSet(opts.IncludeFstypes),
+		excludeFstype: toSet(excludeFstypes),
+		includeMount:  toSet(opts.IncludeMounts),
+		excludeMount:  toSet(opts.ExcludeMounts),
+	}
+}
+
+// Allow reports whether a partition with the given mountpoint and fstype
+// should be included in output.
+func (f *Filter) Allow(mountpoint, fstype string) bool {
+	if len(f.includeFstype) > 0 {
+		if !f.includeFstype[fstype] {
+			return false
+		}
+	} else if f.excludeFstype[fstype] {
+		return false
+	}
+
+	if len(f.includeMount) > 0 {
+		if !f.includeMount[mountpoint] {
+			return false
+		}
+	} else if f.excludeMount[mountpoint] {
+		return false
+	}
+
+	return true
+}
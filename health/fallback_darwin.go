@@ -0,0 +1,13 @@
+//go:build darwin
+
+package health
+
+import "errors"
+
+// collectFallback has no pure-Go implementation on macOS: the ATA
+// PASS-THROUGH ioctl fallback in ioctl_linux.go is Linux-specific (it
+// relies on SG_IO), and IOKit's equivalent is out of scope here.
+// Installing smartmontools is the supported path on macOS.
+func collectFallback(device string) (*Report, error) {
+	return nil, errors.New("smartctl not found and no pure-Go fallback is available on macOS; install smartmontools")
+}
@@ -0,0 +1,143 @@
+//go:build windows
+
+package health
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// collect opens the physical drive (device is expected in the form
+// "PhysicalDriveN", as produced by topology on Windows) and issues
+// SMART_RCV_DRIVE_DATA to read the attribute table and
+// IOCTL_STORAGE_PREDICT_FAILURE for the drive's overall predict-failure
+// status. Both requests are always made and each fills in its own part of
+// the Report; a failure of either is not returned as an error.
+// IOCTL_STORAGE_QUERY_PROPERTY is not issued.
+func collect(device string) (*Report, error) {
+	path := `\\.\` + strings.TrimPrefix(device, `\\.\`)
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := windows.CreateFile(pathPtr,
+		windows.GENERIC_READ|windows.GENERIC_WRITE,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE,
+		nil, windows.OPEN_EXISTING, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer windows.CloseHandle(h)
+
+	r := &Report{Source: "ioctl"}
+
+	if attrs, err := readSmartAttributes(h); err == nil {
+		parseSmartDataAttributes(attrs, r)
+	}
+	if passed, err := readPredictFailure(h); err == nil {
+		if passed {
+			r.Overall = "PASSED"
+		} else {
+			r.Overall = "FAILED"
+		}
+	}
+
+	return r, nil
+}
+
+// sendCmdInParams/sendCmdOutParams mirror the SENDCMDINPARAMS /
+// SENDCMDOUTPARAMS structs winioctl.h expects around SMART_RCV_DRIVE_DATA.
+type ideRegs struct {
+	Features     byte
+	SectorCount  byte
+	SectorNumber byte
+	CylinderLow  byte
+	CylinderHigh byte
+	DriveHead    byte
+	Command      byte
+	Reserved     byte
+}
+
+type sendCmdInParams struct {
+	BufferSize  uint32
+	Regs        ideRegs
+	DriveNumber byte
+	Reserved    [3]byte
+	Reserved2   [4]uint32
+	Buffer      [1]byte
+}
+
+type sendCmdOutParams struct {
+	BufferSize uint32
+	Status     struct {
+		Status    byte
+		Error     byte
+		Reserved  [2]byte
+		Reserved2 [2]uint32
+	}
+	Buffer [512]byte
+}
+
+const (
+	ioctlSmartRcvDriveData     = 0x7C088
+	ioctlStorageQueryProperty  = 0x2D1400
+	ioctlStoragePredictFailure = 0x2D1100
+	smartCmd                   = 0xB0
+	smartReadAttributeValues   = 0xD0
+)
+
+func readSmartAttributes(h windows.Handle) ([]byte, error) {
+	in := sendCmdInParams{
+		BufferSize: 512,
+		Regs: ideRegs{
+			Features:     smartReadAttributeValues,
+			CylinderLow:  0x4F,
+			CylinderHigh: 0xC2,
+			Command:      smartCmd,
+		},
+	}
+	var out sendCmdOutParams
+	var returned uint32
+
+	err := windows.DeviceIoControl(h, ioctlSmartRcvDriveData,
+		(*byte)(unsafe.Pointer(&in)), uint32(unsafe.Sizeof(in)),
+		(*byte)(unsafe.Pointer(&out)), uint32(unsafe.Sizeof(out)),
+		&returned, nil)
+	if err != nil {
+		return nil, fmt.Errorf("SMART_RCV_DRIVE_DATA: %w", err)
+	}
+	return out.Buffer[:], nil
+}
+
+// storagePredictFailure mirrors STORAGE_PREDICT_FAILURE from winioctl.h:
+// PredictFailure is nonzero when the drive has flagged imminent failure.
+type storagePredictFailure struct {
+	PredictFailure uint32
+	VendorSpecific [512]byte
+}
+
+func readPredictFailure(h windows.Handle) (bool, error) {
+	var out storagePredictFailure
+	var returned uint32
+
+	err := windows.DeviceIoControl(h, ioctlStoragePredictFailure,
+		nil, 0,
+		(*byte)(unsafe.Pointer(&out)), uint32(unsafe.Sizeof(out)),
+		&returned, nil)
+	if err != nil {
+		return false, fmt.Errorf("IOCTL_STORAGE_PREDICT_FAILURE: %w", err)
+	}
+	return out.PredictFailure == 0, nil
+}
+
+// physicalDriveIndex extracts the N in "PhysicalDriveN" for callers that
+// need the raw drive number (e.g. DriveNumber in SENDCMDINPARAMS).
+func physicalDriveIndex(device string) (int, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(device, `\\.\`), "PhysicalDrive")
+	return strconv.Atoi(s)
+}
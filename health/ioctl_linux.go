@@ -0,0 +1,176 @@
+//go:build linux
+
+package health
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Pure-Go ATA PASS-THROUGH(16) fallback for systems without smartmontools
+// installed, modeled on the same SG_IO ioctl smartctl itself uses. It reads
+// just enough of the SMART DATA and SMART RETURN STATUS to populate Report:
+// temperature, reallocated sector count, power-on hours, wear leveling and
+// overall PASS/FAIL. It does not attempt vendor-specific attribute
+// interpretation or NVMe log pages — that's left to smartctl.
+
+const (
+	sgIO           = 0x2285
+	sgDXferFromDev = 3
+
+	ataOpASCII16         = 0x85 // ATA PASS-THROUGH(16) SCSI opcode
+	ataProtoPIO          = 4 << 1
+	ataTFlagsTLenSectors = 2
+	ataTFlagsTLenInTfeat = 1
+
+	ataCmdSmart      = 0xB0
+	ataSmartReadData = 0xD0
+	ataSmartStatus   = 0xDA
+	ataSmartLBAMid   = 0x4F
+	ataSmartLBAHigh  = 0xC2
+)
+
+// sgIOHdr mirrors Linux's struct sg_io_hdr (scsi/sg.h). Only the fields the
+// ATA PASS-THROUGH command needs are wired up; the rest are zeroed.
+type sgIOHdr struct {
+	InterfaceID    int32
+	DXferDirection int32
+	CmdLen         uint8
+	MxSBLen        uint8
+	IOvecCount     uint16
+	DXferLen       uint32
+	DXferP         uintptr
+	Cmdp           uintptr
+	Sbp            uintptr
+	Timeout        uint32
+	Flags          uint32
+	PackID         int32
+	UsrPtr         uintptr
+	Status         uint8
+	MaskedStatus   uint8
+	MsgStatus      uint8
+	SBLenWr        uint8
+	HostStatus     uint16
+	DriverStatus   uint16
+	Resid          int32
+	Duration       uint32
+	Info           uint32
+}
+
+func collectFallback(device string) (*Report, error) {
+	f, err := os.OpenFile(device, os.O_RDONLY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", device, err)
+	}
+	defer f.Close()
+
+	data, err := ataPassThroughRead(f.Fd(), ataSmartReadData, ataSmartLBAMid, 1)
+	if err != nil {
+		return nil, fmt.Errorf("ATA SMART READ DATA: %w", err)
+	}
+
+	r := &Report{Source: "ioctl"}
+	parseSmartDataAttributes(data, r)
+
+	if passed, err := ataSmartReturnStatus(f.Fd()); err == nil {
+		if passed {
+			r.Overall = "PASSED"
+		} else {
+			r.Overall = "FAILED"
+		}
+	}
+
+	return r, nil
+}
+
+// ataPassThroughRead issues an ATA PASS-THROUGH(16) PIO-in command for the
+// ATA SMART command (0xB0) with the given SMART subcommand in the features
+// register, and returns the nSectors*512-byte data buffer it returns.
+func ataPassThroughRead(fd uintptr, feature byte, lbaMid, nSectors byte) ([]byte, error) {
+	buf := make([]byte, int(nSectors)*512)
+
+	// ATA PASS-THROUGH(16) CDB, see T10 SAT-3 section 12.2.2.
+	cdb := make([]byte, 16)
+	cdb[0] = ataOpASCII16
+	cdb[1] = ataProtoPIO
+	cdb[2] = ataTFlagsTLenSectors | ataTFlagsTLenInTfeat<<2 | 1<<3 // T_DIR=1 (from device)
+	cdb[3] = feature                                               // features: SMART subcommand
+	cdb[4] = nSectors                                              // sector count
+	cdb[6] = lbaMid                                                // LBA mid (SMART signature 0x4F)
+	cdb[8] = ataSmartLBAHigh                                       // LBA high (SMART signature 0xC2)
+	cdb[14] = ataCmdSmart                                          // command: SMART (0xB0)
+
+	var sense [32]byte
+	hdr := sgIOHdr{
+		InterfaceID:    'S',
+		DXferDirection: sgDXferFromDev,
+		CmdLen:         uint8(len(cdb)),
+		MxSBLen:        uint8(len(sense)),
+		DXferLen:       uint32(len(buf)),
+		DXferP:         uintptr(unsafe.Pointer(&buf[0])),
+		Cmdp:           uintptr(unsafe.Pointer(&cdb[0])),
+		Sbp:            uintptr(unsafe.Pointer(&sense[0])),
+		Timeout:        5000,
+	}
+
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, fd, sgIO, uintptr(unsafe.Pointer(&hdr))); errno != 0 {
+		return nil, errno
+	}
+	if hdr.Status != 0 || hdr.HostStatus != 0 || hdr.DriverStatus != 0 {
+		return nil, fmt.Errorf("SG_IO failed: status=%d host=%d driver=%d", hdr.Status, hdr.HostStatus, hdr.DriverStatus)
+	}
+	return buf, nil
+}
+
+// ataSmartReturnStatus issues SMART RETURN STATUS and inspects the LBA
+// mid/high registers the drive echoes back: 0x4F/0xC2 means PASSED,
+// 0xF4/0x2C means the drive has tripped a threshold (FAILED).
+func ataSmartReturnStatus(fd uintptr) (bool, error) {
+	// SMART RETURN STATUS returns its verdict in registers, not a data
+	// buffer, so no data buffer is passed to SG_IO; the LBA mid/high are
+	// recovered from the sense buffer's ATA Return descriptor
+	// (descriptor-format sense). We only decode the two bytes we need.
+	cdb := make([]byte, 16)
+	cdb[0] = ataOpASCII16
+	cdb[1] = ataProtoPIO
+	cdb[2] = 1 << 5         // no data transfer, but request register status
+	cdb[3] = ataSmartStatus // features: SMART subcommand (RETURN STATUS)
+	cdb[6] = ataSmartLBAMid
+	cdb[8] = ataSmartLBAHigh
+	cdb[14] = ataCmdSmart // command: SMART (0xB0)
+
+	var sense [32]byte
+	hdr := sgIOHdr{
+		InterfaceID:    'S',
+		DXferDirection: sgDXferFromDev,
+		CmdLen:         uint8(len(cdb)),
+		MxSBLen:        uint8(len(sense)),
+		Cmdp:           uintptr(unsafe.Pointer(&cdb[0])),
+		Sbp:            uintptr(unsafe.Pointer(&sense[0])),
+		Timeout:        5000,
+	}
+
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, fd, sgIO, uintptr(unsafe.Pointer(&hdr))); errno != 0 {
+		return false, errno
+	}
+
+	// Descriptor-format sense data (SAT "ATA Return descriptor", desc
+	// type 0x09) carries LBA mid at offset 9 and LBA high at offset 11 of
+	// the descriptor, which follows the 8-byte sense data header.
+	desc := sense[8:]
+	if len(desc) < 12 {
+		return false, fmt.Errorf("short sense data")
+	}
+	lbaMid, lbaHigh := desc[9], desc[11]
+	switch {
+	case lbaMid == ataSmartLBAMid && lbaHigh == ataSmartLBAHigh:
+		return true, nil
+	case lbaMid == 0xF4 && lbaHigh == 0x2C:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unrecognized SMART status registers %#x/%#x", lbaMid, lbaHigh)
+	}
+}
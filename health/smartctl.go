@@ -0,0 +1,107 @@
+//go:build linux || darwin
+
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+)
+
+// collect shells out to `smartctl --json` when it is available on PATH,
+// falling back to the pure-Go ioctl reader on Linux (see ioctl_linux.go)
+// when smartmontools isn't installed.
+func collect(device string) (*Report, error) {
+	path, err := exec.LookPath("smartctl")
+	if err != nil {
+		return collectFallback(device)
+	}
+	return collectSmartctl(path, device)
+}
+
+// smartctlOutput is the slice of `smartctl -a --json` we care about.
+// smartctl's JSON schema has far more fields than this; we only decode
+// what feeds into Report.
+type smartctlOutput struct {
+	SmartStatus struct {
+		// Passed is a pointer so a JSON document that omits smart_status
+		// entirely (some transports/devices don't report it) can be told
+		// apart from one that reports an actual FAILED verdict.
+		Passed *bool `json:"passed"`
+	} `json:"smart_status"`
+	Temperature struct {
+		Current int `json:"current"`
+	} `json:"temperature"`
+	PowerOnTime struct {
+		Hours uint64 `json:"hours"`
+	} `json:"power_on_time"`
+	AtaSmartAttributes struct {
+		Table []struct {
+			ID       int    `json:"id"`
+			Name     string `json:"name"`
+			Value    int    `json:"value"`
+			RawValue uint64 `json:"raw_value"`
+		} `json:"table"`
+	} `json:"ata_smart_attributes"`
+	NvmeSmartHealthInformationLog struct {
+		PercentageUsed  int `json:"percentage_used"`
+		CriticalWarning int `json:"critical_warning"`
+	} `json:"nvme_smart_health_information_log"`
+}
+
+const (
+	attrReallocatedSectorCount = 5
+	attrWearLevelingCount      = 177
+	attrMediaWearoutIndicator  = 173
+)
+
+func collectSmartctl(smartctlPath, device string) (*Report, error) {
+	out, err := exec.Command(smartctlPath, "--json", "-a", device).Output()
+	// smartctl uses its exit code as a bitmask of warnings, so a non-zero
+	// exit with valid JSON on stdout is normal and not an error.
+	if len(out) == 0 && err != nil {
+		return nil, fmt.Errorf("smartctl: %w", err)
+	}
+
+	var parsed smartctlOutput
+	if jsonErr := json.Unmarshal(out, &parsed); jsonErr != nil {
+		return nil, fmt.Errorf("smartctl: decode json: %w", jsonErr)
+	}
+
+	r := &Report{
+		TemperatureC: parsed.Temperature.Current,
+		PowerOnHours: parsed.PowerOnTime.Hours,
+		Source:       "smartctl",
+	}
+	switch {
+	case parsed.SmartStatus.Passed == nil:
+		// Not reported by this device/transport; leave Overall empty
+		// rather than defaulting to a false "FAILED".
+	case *parsed.SmartStatus.Passed:
+		r.Overall = "PASSED"
+	default:
+		r.Overall = "FAILED"
+	}
+
+	for _, attr := range parsed.AtaSmartAttributes.Table {
+		switch attr.ID {
+		case attrReallocatedSectorCount:
+			r.ReallocatedSectors = attr.RawValue
+		case attrWearLevelingCount, attrMediaWearoutIndicator:
+			// attr.Value is the normalized 0-100 attribute value;
+			// RawValue is a vendor-specific counter, not a percentage.
+			r.WearLevelPct = attr.Value
+		}
+	}
+
+	// NVMe drives report wear via percentage_used (0 = new, 100 = worn
+	// out) rather than an ATA wear-leveling attribute.
+	if parsed.NvmeSmartHealthInformationLog.PercentageUsed > 0 {
+		r.WearLevelPct = 100 - parsed.NvmeSmartHealthInformationLog.PercentageUsed
+	}
+	if parsed.NvmeSmartHealthInformationLog.CriticalWarning != 0 {
+		r.Overall = "FAILED"
+	}
+
+	return r, nil
+}
@@ -5,57 +5,126 @@ import (
 	"flag"
 	"fmt"
 	"html/template"
+	"io"
 	"os"
+	"path"
+	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
 	"github.com/shirou/gopsutil/v3/disk"
+
+	"github.com/m4rkux/diskinfo/filter"
+	"github.com/m4rkux/diskinfo/health"
+	"github.com/m4rkux/diskinfo/rules"
+	"github.com/m4rkux/diskinfo/topology"
 )
 
-type DiskInfo struct {
-	Device     string  `json:"device"`
-	Mountpoint string  `json:"mountpoint"`
-	TotalGB    float64 `json:"total_gb"`
-	FreeGB     float64 `json:"free_gb"`
-	FreePct    float64 `json:"free_pct"`
+// DiskNode is a physical disk and the mounted partitions gopsutil found
+// underneath it, as discovered by the topology package. This replaces the
+// old flat, trailing-digit-deduplicated DiskInfo list.
+type DiskNode struct {
+	Name       string          `json:"name"`
+	SizeGB     float64         `json:"size_gb"`
+	Rotational bool            `json:"rotational"`
+	Model      string          `json:"model,omitempty"`
+	Serial     string          `json:"serial,omitempty"`
+	Health     *health.Report  `json:"health,omitempty"`
+	IO         *DiskIOStats    `json:"io,omitempty"`
+	Partitions []PartitionInfo `json:"partitions"`
+}
+
+// PartitionInfo is a single mounted partition within a DiskNode.
+type PartitionInfo struct {
+	Device     string         `json:"device"`
+	Mountpoint string         `json:"mountpoint"`
+	Fstype     string         `json:"fstype"`
+	TotalGB    float64        `json:"total_gb"`
+	FreeGB     float64        `json:"free_gb"`
+	FreePct    float64        `json:"free_pct"`
+	Severity   rules.Severity `json:"severity"`
+}
+
+// DiskIOStats is a rate computed from two disk.IOCounters() samples taken
+// -interval apart: IOPS, throughput and average service time per device.
+// ReadBytesTotal/WriteBytesTotal are the raw cumulative counters from the
+// most recent sample, exposed as-is for the Prometheus exporter, which
+// wants counters (so it can rate() them) rather than pre-computed rates.
+type DiskIOStats struct {
+	ReadIOPS        float64 `json:"read_iops"`
+	WriteIOPS       float64 `json:"write_iops"`
+	ReadMBps        float64 `json:"read_mbps"`
+	WriteMBps       float64 `json:"write_mbps"`
+	AwaitMs         float64 `json:"await_ms"`
+	InProgress      uint64  `json:"iops_in_progress"`
+	ReadBytesTotal  uint64  `json:"read_bytes_total"`
+	WriteBytesTotal uint64  `json:"write_bytes_total"`
+}
+
+// collectOpts bundles the flags that affect what collectDisks gathers, so
+// both the one-shot CLI path and the -serve scrape loop can share it.
+type collectOpts struct {
+	withHealth bool
+	withIO     bool
+	interval   time.Duration
+	filter     *filter.Filter
+	rules      *rules.Engine
 }
 
 func main() {
 	format := flag.String("format", "text", "Output format: text, json, html")
+	withHealth := flag.Bool("health", false, "Collect SMART health diagnostics for each device (requires smartctl or elevated ioctl access)")
+	withIO := flag.Bool("io", false, "Sample disk I/O counters (IOPS, MB/s, await) over -interval")
+	interval := flag.Duration("interval", time.Second, "Sampling interval used by -io")
+	serveAddr := flag.String("serve", "", "Run as an HTTP server on this address exposing /metrics, /api/disks and / (e.g. -serve :9100)")
+	cacheTTL := flag.Duration("cache-ttl", 5*time.Second, "How long -serve caches a collection before re-scraping")
+	fstypes := flag.String("fstype", "", "Comma-separated list of filesystem types to include (default: all except pseudo filesystems)")
+	excludeFstypes := flag.String("exclude-fstype", "", "Comma-separated list of filesystem types to exclude (default: tmpfs, overlay, squashfs and other pseudo filesystems)")
+	mounts := flag.String("mount", "", "Comma-separated list of mountpoints to include (default: all)")
+	excludeMounts := flag.String("exclude-mount", "", "Comma-separated list of mountpoints to exclude")
+	warnPct := flag.Float64("warn-pct", 20, "Warn when free space drops to or below this percentage")
+	critPct := flag.Float64("crit-pct", 10, "Exit critical when free space drops to or below this percentage")
+	warnFreeGB := flag.Float64("warn-free-gb", 0, "Warn when free space drops to or below this many GB (0 disables)")
+	critFreeGB := flag.Float64("crit-free-gb", 0, "Exit critical when free space drops to or below this many GB (0 disables)")
+	rulesFile := flag.String("rules", "", "YAML file with per-mountpoint threshold overrides")
 	flag.Parse()
 
-	partitions, err := disk.Partitions(false)
+	rulesConfig, err := rules.LoadConfig(*rulesFile)
 	if err != nil {
-		fmt.Println("Error getting partitions:", err)
-		return
+		fmt.Println("Error loading rules file:", err)
+		os.Exit(3)
 	}
 
-	var disks []DiskInfo
-	seen := map[string]bool{}
-
-	for _, p := range partitions {
-		diskID := normalizeDeviceID(p.Device)
-
-		if seen[diskID] {
-			continue // Skip already processed partitions
-		}
-		seen[diskID] = true
-
-		usage, err := disk.Usage(p.Mountpoint)
-		if err != nil {
-			continue // skip unmountable or inaccessible partitions
-		}
+	opts := collectOpts{
+		withHealth: *withHealth,
+		withIO:     *withIO,
+		interval:   *interval,
+		filter: filter.New(filter.Options{
+			IncludeFstypes: splitList(*fstypes),
+			ExcludeFstypes: splitList(*excludeFstypes),
+			IncludeMounts:  splitList(*mounts),
+			ExcludeMounts:  splitList(*excludeMounts),
+		}),
+		rules: rules.New(rules.Thresholds{
+			WarnPct:    *warnPct,
+			CritPct:    *critPct,
+			WarnFreeGB: *warnFreeGB,
+			CritFreeGB: *critFreeGB,
+		}, rulesConfig),
+	}
 
-		info := DiskInfo{
-			Device:     diskID,
-			Mountpoint: p.Mountpoint,
-			TotalGB:    float64(usage.Total) / 1e9,
-			FreeGB:     float64(usage.Free) / 1e9,
-			FreePct:    float64(usage.Free) / float64(usage.Total) * 100,
-		}
+	if *serveAddr != "" {
+		serve(*serveAddr, opts, *cacheTTL)
+		return
+	}
 
-		disks = append(disks, info)
+	disks, err := collectDisks(opts)
+	if err != nil {
+		fmt.Println("Error collecting disk info:", err)
+		os.Exit(3)
 	}
 
 	switch *format {
@@ -68,90 +137,454 @@ func main() {
 	default:
 		outputText(disks)
 	}
+
+	overall, summary := summarize(disks)
+	if *format == "json" || *format == "html" {
+		fmt.Fprintln(os.Stderr, summary)
+	} else {
+		fmt.Println(summary)
+	}
+	os.Exit(overall.ExitCode())
+}
+
+// summarize computes the worst Severity across every reported partition
+// and a one-line, machine-parseable status summary suitable for cron or
+// CI logs, in the spirit of a nagios plugin's final status line.
+func summarize(disks []DiskNode) (rules.Severity, string) {
+	var overall rules.Severity
+	var ok, warn, crit int
+	for _, d := range disks {
+		for _, p := range d.Partitions {
+			switch p.Severity {
+			case rules.SeverityCrit:
+				crit++
+			case rules.SeverityWarn:
+				warn++
+			default:
+				ok++
+			}
+			if p.Severity > overall {
+				overall = p.Severity
+			}
+		}
+	}
+	return overall, fmt.Sprintf("DISKINFO %s - ok=%d warn=%d crit=%d", strings.ToUpper(overall.String()), ok, warn, crit)
+}
+
+// collectDisks walks the disk topology, joins it against gopsutil's
+// mounted partitions and usage stats, and optionally enriches each disk
+// with SMART health and/or I/O sampling per opts.
+func collectDisks(opts collectOpts) ([]DiskNode, error) {
+	physicalDisks, err := topology.Discover()
+	if err != nil {
+		return nil, fmt.Errorf("discovering disk topology: %w", err)
+	}
+
+	partitionsByName, err := mountedPartitionsByName()
+	if err != nil {
+		return nil, fmt.Errorf("getting partitions: %w", err)
+	}
+
+	// claimed tracks every mountpoint already attributed to a physical
+	// disk below, so the network/bind-mount pass afterwards knows which
+	// gopsutil partitions are left over.
+	claimed := make(map[string]bool)
+
+	var disks []DiskNode
+	for _, pd := range physicalDisks {
+		node := DiskNode{
+			Name:       pd.Name,
+			SizeGB:     float64(pd.SizeBytes) / 1e9,
+			Rotational: pd.Rotational,
+			Model:      pd.Model,
+			Serial:     pd.Serial,
+		}
+
+		parts := pd.Partitions
+		if len(parts) == 0 {
+			// No /sys/block child carries a "partition" marker, but the
+			// disk itself may still be the mountpoint: a disk formatted
+			// and mounted directly (no partition table), or an LVM/mapper
+			// volume, which sysfs exposes as a disk (e.g. "dm-0") rather
+			// than a partition of one.
+			if _, ok := partitionsByName[pd.Name]; ok {
+				parts = []topology.Partition{{Name: pd.Name, SizeBytes: pd.SizeBytes}}
+			}
+		}
+
+		for _, part := range parts {
+			// A topology.Partition's Mountpoint/Fstype are populated
+			// directly by backends that already know the mapping
+			// (Windows, and the gopsutil-based backend on other non-Linux
+			// platforms); on Linux they are empty, so join against every
+			// gopsutil partition backed by this device name, since the
+			// same device can be mounted at more than one mountpoint
+			// (bind mounts, btrfs subvolumes).
+			stats := []disk.PartitionStat{{Mountpoint: part.Mountpoint, Fstype: part.Fstype}}
+			if part.Mountpoint == "" {
+				stats = partitionsByName[part.Name]
+			}
+
+			for _, stat := range stats {
+				mountpoint, fstype := stat.Mountpoint, stat.Fstype
+				if mountpoint == "" {
+					continue // not mounted, nothing to report
+				}
+				claimed[mountpoint] = true
+
+				if opts.filter != nil && !opts.filter.Allow(mountpoint, fstype) {
+					continue
+				}
+
+				usage, err := disk.Usage(mountpoint)
+				if err != nil {
+					continue // skip unmountable or inaccessible partitions
+				}
+
+				freeGB := float64(usage.Free) / 1e9
+				freePct := float64(usage.Free) / float64(usage.Total) * 100
+
+				info := PartitionInfo{
+					Device:     part.Name,
+					Mountpoint: mountpoint,
+					Fstype:     fstype,
+					TotalGB:    float64(usage.Total) / 1e9,
+					FreeGB:     freeGB,
+					FreePct:    freePct,
+				}
+				if opts.rules != nil {
+					info.Severity = opts.rules.Evaluate(mountpoint, freeGB, freePct)
+				}
+				node.Partitions = append(node.Partitions, info)
+			}
+		}
+
+		if len(node.Partitions) == 0 {
+			continue // disk has nothing mounted, skip like before
+		}
+
+		if opts.withHealth {
+			if report, err := health.Collect(devicePath(node.Name)); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: health check for %s: %v\n", node.Name, err)
+			} else {
+				node.Health = report
+			}
+		}
+
+		disks = append(disks, node)
+	}
+
+	if networkNode := collectNetworkMounts(partitionsByName, claimed, opts); networkNode != nil {
+		disks = append(disks, *networkNode)
+	}
+
+	if opts.withIO {
+		ioStats, err := sampleIO(disks, opts.interval)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: sampling disk I/O:", err)
+		} else {
+			for i := range disks {
+				disks[i].IO = ioStats[disks[i].Name]
+			}
+		}
+	}
+
+	return disks, nil
 }
 
-func outputText(disks []DiskInfo) {
+// collectNetworkMounts gathers every gopsutil partition in partitionsByName
+// that wasn't claimed while walking the physical disk topology: NFS, CIFS,
+// sshfs and other network mounts have no backing /sys/block device, and
+// bind mounts of a mountpoint that isn't itself backed by a known device
+// fall in the same bucket. They're reported together under a single
+// synthetic "network" node rather than dropped, since disk.Partitions(true)
+// (as opposed to the false used before this) is what surfaces them at all.
+// Returns nil if there's nothing left over.
+func collectNetworkMounts(partitionsByName map[string][]disk.PartitionStat, claimed map[string]bool, opts collectOpts) *DiskNode {
+	var partitions []PartitionInfo
+	for _, stats := range partitionsByName {
+		for _, stat := range stats {
+			mountpoint, fstype := stat.Mountpoint, stat.Fstype
+			if mountpoint == "" || claimed[mountpoint] {
+				continue
+			}
+			claimed[mountpoint] = true
+
+			if opts.filter != nil && !opts.filter.Allow(mountpoint, fstype) {
+				continue
+			}
+
+			usage, err := disk.Usage(mountpoint)
+			if err != nil {
+				continue // skip unmountable or inaccessible partitions
+			}
+
+			freeGB := float64(usage.Free) / 1e9
+			freePct := float64(usage.Free) / float64(usage.Total) * 100
+
+			info := PartitionInfo{
+				Device:     stat.Device,
+				Mountpoint: mountpoint,
+				Fstype:     fstype,
+				TotalGB:    float64(usage.Total) / 1e9,
+				FreeGB:     freeGB,
+				FreePct:    freePct,
+			}
+			if opts.rules != nil {
+				info.Severity = opts.rules.Evaluate(mountpoint, freeGB, freePct)
+			}
+			partitions = append(partitions, info)
+		}
+	}
+	if len(partitions) == 0 {
+		return nil
+	}
+	return &DiskNode{Name: "network", Partitions: partitions}
+}
+
+// mountedPartitionsByName indexes gopsutil's mounted partitions by their
+// bare device name (e.g. "sda1"), so a topology.Partition discovered from
+// /sys/block can be joined against it to find its mountpoint(s). The value
+// is a slice because the same device can be mounted at more than one
+// mountpoint (bind mounts, btrfs subvolumes), and collapsing those to a
+// single entry would silently drop all but the last. Entries are also
+// indexed under the device's resolved name, since /dev/mapper/<lv> and
+// other symlinked device names resolve to the dm-N (etc.) name that
+// /sys/block and topology.Disk.Name actually use.
+func mountedPartitionsByName() (map[string][]disk.PartitionStat, error) {
+	partitions, err := disk.Partitions(true)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string][]disk.PartitionStat, len(partitions))
+	for _, p := range partitions {
+		name := path.Base(p.Device)
+		byName[name] = append(byName[name], p)
+		if resolved, err := filepath.EvalSymlinks(p.Device); err == nil {
+			if resolvedName := path.Base(resolved); resolvedName != name {
+				byName[resolvedName] = append(byName[resolvedName], p)
+			}
+		}
+	}
+	return byName, nil
+}
+
+// splitList turns a comma-separated flag value into a slice, dropping
+// empty elements so an unset flag yields nil rather than [""].
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// devicePath turns a topology.Disk.Name (e.g. "sda", "PhysicalDrive0")
+// into the path health.Collect expects to open.
+func devicePath(name string) string {
+	if runtime.GOOS == "windows" {
+		return name
+	}
+	return "/dev/" + name
+}
+
+func outputText(disks []DiskNode) {
 	title := color.New(color.FgCyan, color.Bold).SprintFunc()
 	header := color.New(color.FgGreen, color.Bold).SprintFunc()
 	printInfo := color.New(color.FgWhite).SprintFunc()
 
 	fmt.Println(title("\n📦 Disk Usage Summary\n"))
 
-	for _, info := range disks {
-		fmt.Printf("%s\n", header(fmt.Sprintf("🔹 Device: %s", info.Device)))
-		fmt.Printf("   Mountpoint: %s\n", printInfo(info.Mountpoint))
-		fmt.Printf("   Total:      %s\n", header(fmt.Sprintf("%.2f GB", info.TotalGB)))
-		fmt.Printf("   Free:       %s\n", getFreeColor(&disk.UsageStat{Free: uint64(info.FreeGB * 1e9), Total: uint64(info.TotalGB * 1e9)})(fmt.Sprintf("%.2f GB (%.2f%%)", info.FreeGB, info.FreePct)))
+	for _, d := range disks {
+		rot := "SSD"
+		if d.Rotational {
+			rot = "HDD"
+		}
+		fmt.Printf("%s\n", header(fmt.Sprintf("🔹 Disk: %s (%s, %.2f GB)", d.Name, rot, d.SizeGB)))
+		if d.Model != "" || d.Serial != "" {
+			fmt.Printf("   %s\n", printInfo(fmt.Sprintf("%s %s", d.Model, d.Serial)))
+		}
+		if d.Health != nil {
+			fmt.Printf("   Health: %s\n", healthBadge(d.Health)(healthSummary(d.Health)))
+		}
+		if d.IO != nil {
+			fmt.Printf("   I/O:    %s\n", printInfo(fmt.Sprintf("%.0f IOPS read / %.0f IOPS write, %.2f MB/s read / %.2f MB/s write, %.2fms await",
+				d.IO.ReadIOPS, d.IO.WriteIOPS, d.IO.ReadMBps, d.IO.WriteMBps, d.IO.AwaitMs)))
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("Partition", "Mountpoint", "Fstype", "Total (GB)", "Free (GB)", "Free (%)")
+		for _, p := range d.Partitions {
+			freeColor := severityColor(p.Severity)
+			table.Append(p.Device, p.Mountpoint, p.Fstype, fmt.Sprintf("%.2f", p.TotalGB), fmt.Sprintf("%.2f", p.FreeGB), freeColor(fmt.Sprintf("%.2f%%", p.FreePct)))
+		}
+		table.Render()
 		fmt.Println()
 	}
 }
 
-func outputJSON(disks []DiskInfo) {
+// healthBadge picks the color for a SMART health summary line based on
+// the report's overall Status.
+func healthBadge(h *health.Report) func(a ...interface{}) string {
+	switch h.Status {
+	case health.StatusCrit:
+		return color.New(color.FgHiRed, color.Bold).SprintFunc()
+	case health.StatusWarn:
+		return color.New(color.FgHiYellow).SprintFunc()
+	case health.StatusOK:
+		return color.New(color.FgHiGreen).SprintFunc()
+	default:
+		return color.New(color.FgHiBlack).SprintFunc()
+	}
+}
+
+func healthSummary(h *health.Report) string {
+	overall := h.Overall
+	if overall == "" {
+		overall = "UNKNOWN"
+	}
+	return fmt.Sprintf("[%s] %s  %d°C  %d realloc  %dh on", h.Status, overall, h.TemperatureC, h.ReallocatedSectors, h.PowerOnHours)
+}
+
+func outputJSON(disks []DiskNode) {
+	if err := writeJSON(os.Stdout, disks); err != nil {
+		fmt.Println("Error encoding JSON:", err)
+	}
+}
+
+func writeJSON(w io.Writer, disks []DiskNode) error {
 	data, err := json.MarshalIndent(disks, "", "  ")
 	if err != nil {
-		fmt.Println("Error encoding JSON:", err)
-		return
+		return err
 	}
-	fmt.Println(string(data))
+	_, err = fmt.Fprintln(w, string(data))
+	return err
 }
 
-func outputHTML(disks []DiskInfo) {
-	const tpl = `
+func outputHTML(disks []DiskNode) {
+	if err := writeHTML(os.Stdout, disks, false); err != nil {
+		fmt.Println("Error generating HTML:", err)
+	}
+}
+
+var htmlTemplate = template.Must(template.New("html").Parse(`
 <!DOCTYPE html>
 <html>
 <head>
 	<title>Disk Usage</title>
+	{{if .AutoRefresh}}<meta http-equiv="refresh" content="5">{{end}}
 	<style>
-		table { border-collapse: collapse; width: 60%; }
+		table { border-collapse: collapse; width: 60%; margin: 0.5em 0 1.5em 1em; }
 		th, td { border: 1px solid #ddd; padding: 8px; text-align: left; }
 		th { background-color: #f2f2f2; }
+		summary { cursor: pointer; font-weight: bold; }
+		tr.ok td:last-child { color: #2e7d32; }
+		tr.warn td:last-child { color: #b8860b; }
+		tr.crit td:last-child { color: #c62828; font-weight: bold; }
 	</style>
 </head>
 <body>
 	<h2>Disk Usage Summary</h2>
-	<table>
-		<tr>
-			<th>Device</th>
-			<th>Mountpoint</th>
-			<th>Total (GB)</th>
-			<th>Free (GB)</th>
-			<th>Free (%)</th>
-		</tr>
-		{{range .}}
-		<tr>
-			<td>{{.Device}}</td>
-			<td>{{.Mountpoint}}</td>
-			<td>{{printf "%.2f" .TotalGB}}</td>
-			<td>{{printf "%.2f" .FreeGB}}</td>
-			<td>{{printf "%.0f" .FreePct}}%</td>
-		</tr>
-		{{end}}
-	</table>
+	{{range .Disks}}
+	<details open>
+		<summary>{{.Name}} &mdash; {{printf "%.2f" .SizeGB}} GB{{if .Model}} &mdash; {{.Model}}{{end}}{{if .Serial}} ({{.Serial}}){{end}}
+			{{if .Health}} &mdash; {{.Health.Overall}} ({{.Health.Status}}){{end}}</summary>
+		<table>
+			<tr>
+				<th>Partition</th>
+				<th>Mountpoint</th>
+				<th>Fstype</th>
+				<th>Total (GB)</th>
+				<th>Free (GB)</th>
+				<th>Free (%)</th>
+			</tr>
+			{{range .Partitions}}
+			<tr class="{{.Severity}}">
+				<td>{{.Device}}</td>
+				<td>{{.Mountpoint}}</td>
+				<td>{{.Fstype}}</td>
+				<td>{{printf "%.2f" .TotalGB}}</td>
+				<td>{{printf "%.2f" .FreeGB}}</td>
+				<td>{{printf "%.0f" .FreePct}}%</td>
+			</tr>
+			{{end}}
+		</table>
+	</details>
+	{{end}}
 </body>
 </html>
-`
-	t := template.Must(template.New("html").Parse(tpl))
-	if err := t.Execute(os.Stdout, disks); err != nil {
-		fmt.Println("Error generating HTML:", err)
-	}
+`))
+
+func writeHTML(w io.Writer, disks []DiskNode, autoRefresh bool) error {
+	return htmlTemplate.Execute(w, struct {
+		Disks       []DiskNode
+		AutoRefresh bool
+	}{disks, autoRefresh})
 }
 
-func normalizeDeviceID(device string) string {
-	if runtime.GOOS == "windows" {
-		return strings.ToUpper(device)
+// sampleIO takes two disk.IOCounters() samples -interval apart and returns
+// the computed per-disk rates, keyed by the same bare name topology uses
+// (e.g. "sda"), so callers can look results up directly by DiskNode.Name.
+func sampleIO(disks []DiskNode, interval time.Duration) (map[string]*DiskIOStats, error) {
+	names := make([]string, 0, len(disks))
+	for _, d := range disks {
+		names = append(names, d.Name)
 	}
 
-	return strings.TrimRightFunc(device, func(r rune) bool {
-		return r >= '0' && r <= '9'
-	})
-}
+	before, err := disk.IOCounters(names...)
+	if err != nil {
+		return nil, fmt.Errorf("sampling first snapshot: %w", err)
+	}
+	time.Sleep(interval)
+	after, err := disk.IOCounters(names...)
+	if err != nil {
+		return nil, fmt.Errorf("sampling second snapshot: %w", err)
+	}
 
-func getFreeColor(usage *disk.UsageStat) func(a ...interface{}) string {
-	freePercent := float64(usage.Free) / float64(usage.Total) * 100
+	seconds := interval.Seconds()
+	result := make(map[string]*DiskIOStats, len(after))
+	for name, a := range after {
+		b, ok := before[name]
+		if !ok {
+			continue
+		}
+
+		readCount := float64(a.ReadCount - b.ReadCount)
+		writeCount := float64(a.WriteCount - b.WriteCount)
+		totalTimeMs := float64((a.ReadTime - b.ReadTime) + (a.WriteTime - b.WriteTime))
+		totalOps := readCount + writeCount
+
+		stats := &DiskIOStats{
+			ReadIOPS:        readCount / seconds,
+			WriteIOPS:       writeCount / seconds,
+			ReadMBps:        float64(a.ReadBytes-b.ReadBytes) / 1e6 / seconds,
+			WriteMBps:       float64(a.WriteBytes-b.WriteBytes) / 1e6 / seconds,
+			InProgress:      a.IopsInProgress,
+			ReadBytesTotal:  a.ReadBytes,
+			WriteBytesTotal: a.WriteBytes,
+		}
+		if totalOps > 0 {
+			stats.AwaitMs = totalTimeMs / totalOps
+		}
+		result[name] = stats
+	}
+	return result, nil
+}
 
-	switch {
-	case freePercent < 10:
-		return color.New(color.FgHiRed).SprintFunc()
+// severityColor picks the color for a partition's free-space column based
+// on its rules.Severity, mirroring healthBadge's pattern for SMART status.
+func severityColor(s rules.Severity) func(a ...interface{}) string {
+	switch s {
+	case rules.SeverityCrit:
+		return color.New(color.FgHiRed, color.Bold).SprintFunc()
+	case rules.SeverityWarn:
+		return color.New(color.FgHiYellow).SprintFunc()
 	default:
 		return color.New(color.FgHiGreen).SprintFunc()
 	}
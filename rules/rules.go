@@ -0,0 +1,141 @@
+// Package rules evaluates free-space thresholds against partitions and
+// produces a nagios-style Severity, generalizing the single hard-coded
+// "freePercent < 10" check main.go used to have into something configurable
+// per mountpoint.
+package rules
+
+import (
+	"encoding/json"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Severity is shared by every output path: JSON gains a "severity" field,
+// HTML rows get a matching CSS class, and the process exit code is the
+// worst Severity seen across all partitions.
+type Severity int
+
+const (
+	SeverityOK Severity = iota
+	SeverityWarn
+	SeverityCrit
+)
+
+// String renders the lowercase form used in JSON and CSS classes.
+func (s Severity) String() string {
+	switch s {
+	case SeverityCrit:
+		return "crit"
+	case SeverityWarn:
+		return "warn"
+	default:
+		return "ok"
+	}
+}
+
+// ExitCode maps Severity to the nagios plugin convention: 0 OK, 1
+// WARNING, 2 CRITICAL.
+func (s Severity) ExitCode() int {
+	return int(s)
+}
+
+// MarshalJSON renders Severity as its lowercase string form ("ok", "warn",
+// "crit") rather than the underlying int.
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// Thresholds are the free-space limits that trigger each Severity. In the
+// merged thresholds Evaluate applies, a zero value for a field means "don't
+// check this dimension"; in a Config section, a zero field means "not set"
+// and the value beneath it (from the flags or "default:") is kept. Pct
+// fields are percentages (0-100); the GB fields are absolute gigabytes free.
+type Thresholds struct {
+	WarnPct    float64 `yaml:"warn_pct"`
+	CritPct    float64 `yaml:"crit_pct"`
+	WarnFreeGB float64 `yaml:"warn_free_gb"`
+	CritFreeGB float64 `yaml:"crit_free_gb"`
+}
+
+// Config is the rules engine's configuration: a default Thresholds plus
+// optional per-mountpoint overrides, e.g. so /boot can be stricter than
+// /home. Loaded from YAML via LoadConfig.
+type Config struct {
+	Default     Thresholds            `yaml:"default"`
+	Mountpoints map[string]Thresholds `yaml:"mountpoints"`
+}
+
+// LoadConfig reads a YAML rules file. An empty path returns a zero Config
+// (no file-based overrides), which is valid input to New.
+func LoadConfig(path string) (Config, error) {
+	if path == "" {
+		return Config{}, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Engine evaluates partitions against a Config, falling back to the
+// Thresholds passed to New (typically the -warn-pct/-crit-pct/etc CLI
+// flags) wherever the config doesn't set a field.
+type Engine struct {
+	cfg Config
+}
+
+// New builds an Engine. flagDefaults are merged in as the base default,
+// with any "default:" section in cfg overriding individual non-zero
+// fields, and per-mountpoint entries overriding both.
+func New(flagDefaults Thresholds, cfg Config) *Engine {
+	merged := cfg
+	merged.Default = mergeThresholds(flagDefaults, cfg.Default)
+	return &Engine{cfg: merged}
+}
+
+// mergeThresholds returns base with any non-zero field in override applied
+// on top.
+func mergeThresholds(base, override Thresholds) Thresholds {
+	if override.WarnPct != 0 {
+		base.WarnPct = override.WarnPct
+	}
+	if override.CritPct != 0 {
+		base.CritPct = override.CritPct
+	}
+	if override.WarnFreeGB != 0 {
+		base.WarnFreeGB = override.WarnFreeGB
+	}
+	if override.CritFreeGB != 0 {
+		base.CritFreeGB = override.CritFreeGB
+	}
+	return base
+}
+
+// Evaluate returns the Severity for a partition with the given mountpoint,
+// free space and free percentage, using any per-mountpoint override in
+// the config on top of the engine's defaults.
+func (e *Engine) Evaluate(mountpoint string, freeGB, freePct float64) Severity {
+	t := e.cfg.Default
+	if override, ok := e.cfg.Mountpoints[mountpoint]; ok {
+		t = mergeThresholds(t, override)
+	}
+
+	switch {
+	case t.CritPct > 0 && freePct <= t.CritPct:
+		return SeverityCrit
+	case t.CritFreeGB > 0 && freeGB <= t.CritFreeGB:
+		return SeverityCrit
+	case t.WarnPct > 0 && freePct <= t.WarnPct:
+		return SeverityWarn
+	case t.WarnFreeGB > 0 && freeGB <= t.WarnFreeGB:
+		return SeverityWarn
+	default:
+		return SeverityOK
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// scrapeCache holds the most recent collectDisks() result so repeated
+// /metrics scrapes within cacheTTL don't each re-run disk.Usage and (if
+// enabled) a full -interval I/O sample.
+type scrapeCache struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	opts  collectOpts
+	at    time.Time
+	disks []DiskNode
+	err   error
+}
+
+func (c *scrapeCache) get() ([]DiskNode, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.at) < c.ttl && (c.disks != nil || c.err != nil) {
+		return c.disks, c.err
+	}
+	c.disks, c.err = collectDisks(c.opts)
+	c.at = time.Now()
+	return c.disks, c.err
+}
+
+// serve turns diskinfo into a long-running HTTP server: /metrics in
+// Prometheus text exposition format, /api/disks as JSON, and / as the
+// existing HTML view with a short auto-refresh.
+func serve(addr string, opts collectOpts, ttl time.Duration) {
+	cache := &scrapeCache{ttl: ttl, opts: opts}
+
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		disks, err := cache.get()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writeMetrics(w, disks)
+	})
+
+	http.HandleFunc("/api/disks", func(w http.ResponseWriter, r *http.Request) {
+		disks, err := cache.get()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := writeJSON(w, disks); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		disks, err := cache.get()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		if err := writeHTML(w, disks, true); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	log.Printf("diskinfo listening on %s (scrape cache TTL %s)", addr, ttl)
+	log.Fatal(http.ListenAndServe(addr, nil))
+}
+
+// writeMetrics renders disks as Prometheus text exposition format.
+func writeMetrics(w io.Writer, disks []DiskNode) {
+	fmt.Fprintln(w, "# HELP diskinfo_total_bytes Total size of the partition.")
+	fmt.Fprintln(w, "# TYPE diskinfo_total_bytes gauge")
+	fmt.Fprintln(w, "# HELP diskinfo_free_bytes Free space on the partition.")
+	fmt.Fprintln(w, "# TYPE diskinfo_free_bytes gauge")
+	fmt.Fprintln(w, "# HELP diskinfo_free_ratio Free space on the partition as a 0-1 ratio.")
+	fmt.Fprintln(w, "# TYPE diskinfo_free_ratio gauge")
+
+	for _, d := range disks {
+		for _, p := range d.Partitions {
+			labels := fmt.Sprintf(`disk="%s",partition="%s",mountpoint="%s",fstype="%s"`, d.Name, p.Device, p.Mountpoint, p.Fstype)
+			fmt.Fprintf(w, "diskinfo_total_bytes{%s} %d\n", labels, uint64(p.TotalGB*1e9))
+			fmt.Fprintf(w, "diskinfo_free_bytes{%s} %d\n", labels, uint64(p.FreeGB*1e9))
+			fmt.Fprintf(w, "diskinfo_free_ratio{%s} %f\n", labels, p.FreePct/100)
+		}
+	}
+
+	if hasAny(disks, func(d DiskNode) bool { return d.IO != nil }) {
+		fmt.Fprintln(w, "# HELP diskinfo_io_read_bytes_total Cumulative bytes read from the physical disk.")
+		fmt.Fprintln(w, "# TYPE diskinfo_io_read_bytes_total counter")
+		fmt.Fprintln(w, "# HELP diskinfo_io_write_bytes_total Cumulative bytes written to the physical disk.")
+		fmt.Fprintln(w, "# TYPE diskinfo_io_write_bytes_total counter")
+		for _, d := range disks {
+			if d.IO == nil {
+				continue
+			}
+			fmt.Fprintf(w, "diskinfo_io_read_bytes_total{disk=\"%s\"} %d\n", d.Name, d.IO.ReadBytesTotal)
+			fmt.Fprintf(w, "diskinfo_io_write_bytes_total{disk=\"%s\"} %d\n", d.Name, d.IO.WriteBytesTotal)
+		}
+	}
+
+	if hasAny(disks, func(d DiskNode) bool { return d.Health != nil }) {
+		fmt.Fprintln(w, "# HELP diskinfo_health_temperature_celsius SMART reported drive temperature.")
+		fmt.Fprintln(w, "# TYPE diskinfo_health_temperature_celsius gauge")
+		fmt.Fprintln(w, "# HELP diskinfo_health_reallocated_sectors SMART reallocated sector count.")
+		fmt.Fprintln(w, "# TYPE diskinfo_health_reallocated_sectors gauge")
+		for _, d := range disks {
+			if d.Health == nil {
+				continue
+			}
+			fmt.Fprintf(w, "diskinfo_health_temperature_celsius{disk=\"%s\"} %d\n", d.Name, d.Health.TemperatureC)
+			fmt.Fprintf(w, "diskinfo_health_reallocated_sectors{disk=\"%s\"} %d\n", d.Name, d.Health.ReallocatedSectors)
+		}
+	}
+}
+
+func hasAny(disks []DiskNode, pred func(DiskNode) bool) bool {
+	for _, d := range disks {
+		if pred(d) {
+			return true
+		}
+	}
+	return false
+}
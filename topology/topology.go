@@ -0,0 +1,35 @@
+// Package topology discovers the physical-disk structure behind the
+// mountpoints gopsutil's disk.Partitions reports, replacing trailing-digit
+// string trimming (which breaks on NVMe, LVM and mapper device names) with
+// an actual walk of the platform's device tree: /sys/block + udev on
+// Linux, WMI on Windows.
+package topology
+
+// Partition is a single partition of a physical Disk. Mountpoint and
+// Fstype are populated directly by backends that already know the mapping
+// (Windows, via Win32_LogicalDisk); callers on platforms where the backend
+// only sees the block device (Linux) must join these against
+// disk.Partitions themselves, matching on Name.
+type Partition struct {
+	Name       string `json:"name"`
+	SizeBytes  uint64 `json:"size_bytes,omitempty"`
+	Mountpoint string `json:"mountpoint,omitempty"`
+	Fstype     string `json:"fstype,omitempty"`
+}
+
+// Disk is a physical block device and its partitions.
+type Disk struct {
+	Name       string      `json:"name"`
+	SizeBytes  uint64      `json:"size_bytes"`
+	Rotational bool        `json:"rotational"`
+	Model      string      `json:"model,omitempty"`
+	Serial     string      `json:"serial,omitempty"`
+	Partitions []Partition `json:"partitions"`
+}
+
+// Discover returns every physical disk visible to the OS along with its
+// partitions. It dispatches to the platform-specific discover() in
+// topology_linux.go, topology_windows.go or topology_other.go.
+func Discover() ([]Disk, error) {
+	return discover()
+}
@@ -0,0 +1,133 @@
+//go:build linux
+
+package topology
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// skipPrefixes excludes pseudo block devices that never correspond to a
+// physical disk: loop devices and legacy ramdisks.
+var skipPrefixes = []string{"loop", "ram"}
+
+func discover() ([]Disk, error) {
+	entries, err := os.ReadDir("/sys/block")
+	if err != nil {
+		return nil, err
+	}
+
+	var disks []Disk
+	for _, e := range entries {
+		name := e.Name()
+		if skip(name) {
+			continue
+		}
+
+		sysPath := filepath.Join("/sys/block", name)
+		d := Disk{
+			Name:       name,
+			SizeBytes:  readUint(filepath.Join(sysPath, "size")) * 512,
+			Rotational: readUint(filepath.Join(sysPath, "queue", "rotational")) == 1,
+		}
+		if major, minor, ok := readDevNumbers(filepath.Join(sysPath, "dev")); ok {
+			d.Model, d.Serial = readUdevInfo(major, minor)
+		}
+		d.Partitions = readPartitions(sysPath, name)
+
+		disks = append(disks, d)
+	}
+	return disks, nil
+}
+
+func skip(name string) bool {
+	for _, prefix := range skipPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// readPartitions finds the child directories of sysPath that carry a
+// "partition" file (the kernel's own marker for "this subdirectory is a
+// partition of the containing disk", which works uniformly for
+// sda/sda1, nvme0n1/nvme0n1p1 and mapper/LVM devices alike).
+func readPartitions(sysPath, diskName string) []Partition {
+	entries, err := os.ReadDir(sysPath)
+	if err != nil {
+		return nil
+	}
+
+	var partitions []Partition
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		partDir := filepath.Join(sysPath, e.Name())
+		if _, err := os.Stat(filepath.Join(partDir, "partition")); err != nil {
+			continue
+		}
+		partitions = append(partitions, Partition{
+			Name:      e.Name(),
+			SizeBytes: readUint(filepath.Join(partDir, "size")) * 512,
+		})
+	}
+	return partitions
+}
+
+func readUint(path string) uint64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	v, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// readDevNumbers reads the "MAJOR:MINOR" content of /sys/block/<disk>/dev.
+func readDevNumbers(path string) (major, minor int, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, false
+	}
+	parts := strings.SplitN(strings.TrimSpace(string(data)), ":", 2)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	major, errMajor := strconv.Atoi(parts[0])
+	minor, errMinor := strconv.Atoi(parts[1])
+	if errMajor != nil || errMinor != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// readUdevInfo pulls ID_MODEL/ID_SERIAL_SHORT out of udev's device
+// database. It's best-effort: on systems without a running udev (e.g.
+// containers) the file won't exist and we just return empty strings.
+func readUdevInfo(major, minor int) (model, serial string) {
+	f, err := os.Open(filepath.Join("/run/udev/data", "b"+strconv.Itoa(major)+":"+strconv.Itoa(minor)))
+	if err != nil {
+		return "", ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "E:ID_MODEL="):
+			model = strings.TrimPrefix(line, "E:ID_MODEL=")
+		case strings.HasPrefix(line, "E:ID_SERIAL_SHORT="):
+			serial = strings.TrimPrefix(line, "E:ID_SERIAL_SHORT=")
+		}
+	}
+	return model, serial
+}
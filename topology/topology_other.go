@@ -0,0 +1,44 @@
+//go:build !linux && !windows
+
+package topology
+
+import (
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// discover has no device-tree walk on this platform (no /sys/block, no
+// WMI), so it falls back to the old trailing-digit heuristic, grouping
+// gopsutil's partitions by their trimmed device name. This keeps macOS/BSD
+// working without a real topology walk; NVMe/LVM naming edge cases are not
+// handled here, only on Linux.
+func discover() ([]Disk, error) {
+	partitions, err := disk.Partitions(true)
+	if err != nil {
+		return nil, err
+	}
+
+	byDisk := map[string]*Disk{}
+	var order []string
+	for _, p := range partitions {
+		name := strings.TrimRightFunc(p.Device, func(r rune) bool { return r >= '0' && r <= '9' })
+		d, ok := byDisk[name]
+		if !ok {
+			d = &Disk{Name: name}
+			byDisk[name] = d
+			order = append(order, name)
+		}
+		d.Partitions = append(d.Partitions, Partition{
+			Name:       p.Device,
+			Mountpoint: p.Mountpoint,
+			Fstype:     p.Fstype,
+		})
+	}
+
+	disks := make([]Disk, 0, len(order))
+	for _, name := range order {
+		disks = append(disks, *byDisk[name])
+	}
+	return disks, nil
+}
@@ -0,0 +1,77 @@
+//go:build windows
+
+package topology
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yusufpapurcu/wmi"
+)
+
+type win32DiskDrive struct {
+	DeviceID     string
+	Model        string
+	SerialNumber string
+	Size         uint64
+	MediaType    string
+}
+
+type win32DiskPartition struct {
+	DeviceID string
+	Size     uint64
+}
+
+type win32LogicalDiskToPartition struct {
+	DeviceID   string // the logical disk's DeviceID, e.g. "C:"
+	FileSystem string
+}
+
+func discover() ([]Disk, error) {
+	var drives []win32DiskDrive
+	if err := wmi.Query("SELECT DeviceID, Model, SerialNumber, Size, MediaType FROM Win32_DiskDrive", &drives); err != nil {
+		return nil, fmt.Errorf("querying Win32_DiskDrive: %w", err)
+	}
+
+	disks := make([]Disk, 0, len(drives))
+	for _, drive := range drives {
+		d := Disk{
+			Name:       strings.TrimPrefix(drive.DeviceID, `\\.\`),
+			SizeBytes:  drive.Size,
+			Model:      strings.TrimSpace(drive.Model),
+			Serial:     strings.TrimSpace(drive.SerialNumber),
+			Rotational: !strings.Contains(strings.ToUpper(drive.MediaType), "SSD"),
+		}
+
+		var partitions []win32DiskPartition
+		q := fmt.Sprintf(`ASSOCIATORS OF {Win32_DiskDrive.DeviceID='%s'} WHERE AssocClass = Win32_DiskDriveToDiskPartition`, drive.DeviceID)
+		if err := wmi.Query(q, &partitions); err != nil {
+			disks = append(disks, d)
+			continue
+		}
+
+		for _, p := range partitions {
+			part := Partition{Name: p.DeviceID, SizeBytes: p.Size}
+
+			var logical []win32LogicalDiskToPartition
+			lq := fmt.Sprintf(`ASSOCIATORS OF {Win32_DiskPartition.DeviceID='%s'} WHERE AssocClass = Win32_LogicalDiskToPartition`, p.DeviceID)
+			if err := wmi.Query(lq, &logical); err == nil && len(logical) > 0 {
+				part.Mountpoint = logical[0].DeviceID + `\`
+				part.Fstype = logical[0].FileSystem
+			}
+
+			d.Partitions = append(d.Partitions, part)
+		}
+
+		disks = append(disks, d)
+	}
+	return disks, nil
+}
+
+// physicalDriveIndex extracts the N in "PhysicalDriveN", matching the
+// bare Name stored on Disk (the \\.\ prefix is added back by
+// health.Collect on Windows).
+func physicalDriveIndex(name string) (int, error) {
+	return strconv.Atoi(strings.TrimPrefix(name, "PhysicalDrive"))
+}